@@ -2,14 +2,17 @@ package tci
 
 import (
 	"sync"
+	"time"
 
 	"github.com/kevburnsjr/tci-lru/lru"
 )
 
 // Cache is a thread-safe fixed size LRU cache.
 type Cache struct {
-	lru  lru.LRUCache
-	lock sync.RWMutex
+	lru       *lru.LRU
+	lock      sync.RWMutex
+	sweepLock sync.Mutex
+	sweepDone chan struct{}
 }
 
 // New creates an LRU of the given size.
@@ -30,6 +33,19 @@ func NewWithEvict(size int, onEvicted func(key interface{}, value interface{}))
 	return c, nil
 }
 
+// NewWithDefaultTTL constructs a fixed size cache whose entries expire after
+// defaultTTL unless overridden per-entry via AddWithTTL.
+func NewWithDefaultTTL(size int, defaultTTL time.Duration, onEvicted func(key interface{}, value interface{})) (*Cache, error) {
+	l, err := lru.NewLRUWithDefaultTTL(size, defaultTTL, lru.EvictCallback(onEvicted))
+	if err != nil {
+		return nil, err
+	}
+	c := &Cache{
+		lru: l,
+	}
+	return c, nil
+}
+
 // Purge is used to completely clear the cache.
 func (c *Cache) Purge() {
 	c.lock.Lock()
@@ -44,6 +60,15 @@ func (c *Cache) Add(key, value interface{}, tags ...string) (evicted bool) {
 	return c.lru.Add(key, value, tags...)
 }
 
+// AddWithTTL adds a value to the cache that expires after ttl, overriding
+// any default TTL the cache was constructed with. Returns true if an
+// eviction occurred.
+func (c *Cache) AddWithTTL(key, value interface{}, ttl time.Duration, tags ...string) (evicted bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.lru.AddWithTTL(key, value, ttl, tags...)
+}
+
 // Get looks up a key's value from the cache.
 func (c *Cache) Get(key interface{}) (value interface{}, ok bool) {
 	c.lock.Lock()
@@ -52,18 +77,21 @@ func (c *Cache) Get(key interface{}) (value interface{}, ok bool) {
 }
 
 // Contains checks if a key is in the cache, without updating the
-// recent-ness or deleting it for being stale.
+// recent-ness. Takes the write lock, not a read lock: a dead entry
+// (expired, or tombstoned by Invalidate) is physically removed on this
+// path, which mutates the underlying LRU's map and list.
 func (c *Cache) Contains(key interface{}) bool {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	return c.lru.Contains(key)
 }
 
 // Peek returns the key value (or undefined if not found) without updating
-// the "recently used"-ness of the key.
+// the "recently used"-ness of the key. Takes the write lock, not a read
+// lock, for the same reason as Contains.
 func (c *Cache) Peek(key interface{}) (value interface{}, ok bool) {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	return c.lru.Peek(key)
 }
 
@@ -132,3 +160,45 @@ func (c *Cache) Len() int {
 	defer c.lock.RUnlock()
 	return c.lru.Len()
 }
+
+// StartExpirySweeper starts a background goroutine that periodically evicts
+// expired entries, firing the cache's eviction callback for each one. This
+// keeps the cache from being polluted by expired-but-never-touched entries
+// between calls to Get/Peek/Contains, which only evict lazily on access.
+// Calling StartExpirySweeper again replaces any previously running sweeper.
+func (c *Cache) StartExpirySweeper(interval time.Duration) {
+	done := make(chan struct{})
+	c.sweepLock.Lock()
+	if c.sweepDone != nil {
+		close(c.sweepDone)
+	}
+	c.sweepDone = done
+	c.sweepLock.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.lock.Lock()
+				c.lru.SweepExpired()
+				c.lock.Unlock()
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background expiry sweeper started by StartExpirySweeper,
+// if one is running. Safe to call concurrently with StartExpirySweeper or
+// itself; sweepLock, not the data-path lock, guards sweepDone so Stop never
+// has to wait on an in-flight sweep.
+func (c *Cache) Stop() {
+	c.sweepLock.Lock()
+	defer c.sweepLock.Unlock()
+	if c.sweepDone != nil {
+		close(c.sweepDone)
+		c.sweepDone = nil
+	}
+}
@@ -0,0 +1,142 @@
+package tci
+
+import (
+	"sync"
+
+	"github.com/kevburnsjr/tci-lru/lru"
+)
+
+// CacheG is a thread-safe fixed size LRU cache parameterized on key and
+// value types, avoiding the boxing overhead of Cache's interface{} API.
+type CacheG[K comparable, V any] struct {
+	lru  *lru.LRUG[K, V]
+	lock sync.RWMutex
+}
+
+// NewG creates an LRUG of the given size.
+func NewG[K comparable, V any](size int) (*CacheG[K, V], error) {
+	return NewGWithEvict[K, V](size, nil)
+}
+
+// NewGWithEvict constructs a fixed size cache with the given eviction
+// callback.
+func NewGWithEvict[K comparable, V any](size int, onEvicted func(key K, value V)) (*CacheG[K, V], error) {
+	l, err := lru.NewLRUG(size, lru.EvictCallbackG[K, V](onEvicted))
+	if err != nil {
+		return nil, err
+	}
+	c := &CacheG[K, V]{
+		lru: l,
+	}
+	return c, nil
+}
+
+// Purge is used to completely clear the cache.
+func (c *CacheG[K, V]) Purge() {
+	c.lock.Lock()
+	c.lru.Purge()
+	c.lock.Unlock()
+}
+
+// Add adds a value to the cache.  Returns true if an eviction occurred.
+func (c *CacheG[K, V]) Add(key K, value V, tags ...string) (evicted bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.lru.Add(key, value, tags...)
+}
+
+// Get looks up a key's value from the cache.
+func (c *CacheG[K, V]) Get(key K) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.lru.Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the
+// recent-ness or deleting it for being stale.
+func (c *CacheG[K, V]) Contains(key K) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.lru.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *CacheG[K, V]) Peek(key K) (value V, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.lru.Peek(key)
+}
+
+// ContainsOrAdd checks if a key is in the cache  without updating the
+// recent-ness or deleting it for being stale,  and if not, adds the value.
+// Returns whether found and whether an eviction occurred.
+func (c *CacheG[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.lru.Contains(key) {
+		return true, false
+	}
+	evicted = c.lru.Add(key, value)
+	return false, evicted
+}
+
+// Remove removes the provided key from the cache.
+func (c *CacheG[K, V]) Remove(key K) {
+	c.lock.Lock()
+	c.lru.Remove(key)
+	c.lock.Unlock()
+}
+
+// Resize changes the cache size.
+func (c *CacheG[K, V]) Resize(size int) (evicted int) {
+	c.lock.Lock()
+	evicted = c.lru.Resize(size)
+	c.lock.Unlock()
+	return evicted
+}
+
+// Invalidate invalidates a tag, purging all associated keys from the cache.
+func (c *CacheG[K, V]) Invalidate(tags []string) (removed int) {
+	c.lock.Lock()
+	removed = c.lru.Invalidate(tags)
+	c.lock.Unlock()
+	return removed
+}
+
+// FindByTags returns all matching keys for a set of tags.
+func (c *CacheG[K, V]) FindByTags(tags []string) (found []K) {
+	c.lock.Lock()
+	found = c.lru.FindByTags(tags)
+	c.lock.Unlock()
+	return found
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *CacheG[K, V]) RemoveOldest() {
+	c.lock.Lock()
+	c.lru.RemoveOldest()
+	c.lock.Unlock()
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *CacheG[K, V]) Keys() []K {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.lru.Keys()
+}
+
+// Values returns a slice of the values in the cache, from oldest to newest.
+func (c *CacheG[K, V]) Values() []V {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.lru.Values()
+}
+
+// Len returns the number of items in the cache.
+func (c *CacheG[K, V]) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,49 @@
+package tci
+
+import "testing"
+
+func TestCacheG_AddGetInvalidate(t *testing.T) {
+	c, err := NewG[string, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	c.Add("a", 1, "tag-1")
+	c.Add("b", 2)
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected hit for a=1, got %v %v", v, ok)
+	}
+
+	if removed := c.Invalidate([]string{"tag-1"}); removed != 1 {
+		t.Fatalf("expected 1 entry invalidated, got %d", removed)
+	}
+	if c.Contains("a") {
+		t.Fatalf("expected a to be gone after invalidate")
+	}
+	if !c.Contains("b") {
+		t.Fatalf("expected b to survive invalidate")
+	}
+}
+
+func TestCacheG_Values(t *testing.T) {
+	c, err := NewG[string, int](4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("c", 3)
+
+	values := c.Values()
+	want := []int{1, 2, 3}
+	if len(values) != len(want) {
+		t.Fatalf("expected %d values, got %d", len(want), len(values))
+	}
+	for i, v := range want {
+		if values[i] != v {
+			t.Fatalf("expected Values()[%d] = %d, got %d", i, v, values[i])
+		}
+	}
+}
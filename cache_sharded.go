@@ -0,0 +1,227 @@
+package tci
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"runtime"
+	"sync"
+)
+
+// ShardedCache is a thread-safe fixed size LRU cache that partitions keys
+// across N independently-locked Cache shards, so that concurrent callers
+// touching different keys don't contend on a single sync.RWMutex.
+type ShardedCache struct {
+	shards []*Cache
+	seed   maphash.Seed
+	mask   uint64
+
+	tagLock   sync.Mutex
+	tagShards map[string]map[int]bool
+}
+
+// NewShardedCache creates a sharded cache of the given total size, using
+// runtime.GOMAXPROCS rounded up to a power of two as the shard count.
+func NewShardedCache(size int) (*ShardedCache, error) {
+	return NewShardedCacheWithShards(size, runtime.GOMAXPROCS(0))
+}
+
+// NewShardedCacheWithShards creates a sharded cache of the given total
+// size, split across numShards shards (rounded up to a power of two).
+// Each shard is sized size/numShards, with a minimum of 1.
+func NewShardedCacheWithShards(size, numShards int) (*ShardedCache, error) {
+	if size <= 0 {
+		return nil, errors.New("Must provide a positive size")
+	}
+	numShards = nextPow2(numShards)
+
+	shardSize := size / numShards
+	if shardSize < 1 {
+		shardSize = 1
+	}
+
+	shards := make([]*Cache, numShards)
+	for i := range shards {
+		c, err := New(shardSize)
+		if err != nil {
+			return nil, err
+		}
+		shards[i] = c
+	}
+
+	return &ShardedCache{
+		shards:    shards,
+		seed:      maphash.MakeSeed(),
+		mask:      uint64(numShards - 1),
+		tagShards: make(map[string]map[int]bool),
+	}, nil
+}
+
+// nextPow2 rounds n up to the nearest power of two, with a floor of 1.
+func nextPow2(n int) int {
+	if n < 1 {
+		return 1
+	}
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// shardIndex hashes a key to a shard, fast-pathing the common string and
+// []byte key types and falling back to their formatted representation for
+// everything else.
+func (c *ShardedCache) shardIndex(key interface{}) int {
+	var h maphash.Hash
+	h.SetSeed(c.seed)
+	switch k := key.(type) {
+	case string:
+		h.WriteString(k)
+	case []byte:
+		h.Write(k)
+	default:
+		h.WriteString(fmt.Sprintf("%v", k))
+	}
+	return int(h.Sum64() & c.mask)
+}
+
+// Add adds a value to the cache. Returns true if an eviction occurred.
+func (c *ShardedCache) Add(key, value interface{}, tags ...string) (evicted bool) {
+	idx := c.shardIndex(key)
+	evicted = c.shards[idx].Add(key, value, tags...)
+	if len(tags) > 0 {
+		c.trackTags(tags, idx)
+	}
+	return evicted
+}
+
+// trackTags records that shard idx may hold entries carrying tags, so
+// Invalidate and FindByTags can target only the shards that need it.
+func (c *ShardedCache) trackTags(tags []string, idx int) {
+	c.tagLock.Lock()
+	defer c.tagLock.Unlock()
+	for _, tag := range tags {
+		if _, ok := c.tagShards[tag]; !ok {
+			c.tagShards[tag] = make(map[int]bool)
+		}
+		c.tagShards[tag][idx] = true
+	}
+}
+
+// Get looks up a key's value from the cache.
+func (c *ShardedCache) Get(key interface{}) (value interface{}, ok bool) {
+	return c.shards[c.shardIndex(key)].Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the
+// recent-ness. A dead entry (expired, or tombstoned by Invalidate) is
+// removed from its shard on this path.
+func (c *ShardedCache) Contains(key interface{}) bool {
+	return c.shards[c.shardIndex(key)].Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *ShardedCache) Peek(key interface{}) (value interface{}, ok bool) {
+	return c.shards[c.shardIndex(key)].Peek(key)
+}
+
+// Remove removes the provided key from the cache.
+func (c *ShardedCache) Remove(key interface{}) {
+	c.shards[c.shardIndex(key)].Remove(key)
+}
+
+// shardsForTags returns the set of shard indexes that may hold an entry for
+// any of the given tags.
+func (c *ShardedCache) shardsForTags(tags []string) map[int]bool {
+	touched := make(map[int]bool)
+	c.tagLock.Lock()
+	defer c.tagLock.Unlock()
+	for _, tag := range tags {
+		for idx := range c.tagShards[tag] {
+			touched[idx] = true
+		}
+	}
+	return touched
+}
+
+// Invalidate invalidates a tag, purging all associated keys from the
+// cache. Only the shards that may hold a matching entry (any shard that a
+// matching tag was ever added to since the last Purge) are locked; if more
+// than one is affected, they are invalidated concurrently.
+func (c *ShardedCache) Invalidate(tags []string) (removed int) {
+	touched := c.shardsForTags(tags)
+	if len(touched) == 0 {
+		return 0
+	}
+	if len(touched) == 1 {
+		for idx := range touched {
+			return c.shards[idx].Invalidate(tags)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	for idx := range touched {
+		idx := idx
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := c.shards[idx].Invalidate(tags)
+			lock.Lock()
+			removed += n
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+	return removed
+}
+
+// FindByTags returns all matching keys for a set of tags.
+func (c *ShardedCache) FindByTags(tags []string) (found []interface{}) {
+	for idx := range c.shardsForTags(tags) {
+		found = append(found, c.shards[idx].FindByTags(tags)...)
+	}
+	return found
+}
+
+// Purge is used to completely clear the cache.
+func (c *ShardedCache) Purge() {
+	for _, shard := range c.shards {
+		shard.Purge()
+	}
+	c.tagLock.Lock()
+	c.tagShards = make(map[string]map[int]bool)
+	c.tagLock.Unlock()
+}
+
+// Resize changes the cache size, redistributing it evenly across shards.
+func (c *ShardedCache) Resize(size int) (evicted int) {
+	shardSize := size / len(c.shards)
+	if shardSize < 1 {
+		shardSize = 1
+	}
+	for _, shard := range c.shards {
+		evicted += shard.Resize(shardSize)
+	}
+	return evicted
+}
+
+// Keys returns a slice of the keys in the cache, aggregated across shards.
+func (c *ShardedCache) Keys() []interface{} {
+	var keys []interface{}
+	for _, shard := range c.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *ShardedCache) Len() int {
+	var n int
+	for _, shard := range c.shards {
+		n += shard.Len()
+	}
+	return n
+}
@@ -0,0 +1,37 @@
+package tci
+
+import "testing"
+
+func TestShardedCache_AddGet(t *testing.T) {
+	c, err := NewShardedCacheWithShards(64, 4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 32; i++ {
+		c.Add(i, i*2, "even")
+	}
+
+	for i := 0; i < 32; i++ {
+		v, ok := c.Get(i)
+		if !ok || v != i*2 {
+			t.Fatalf("expected hit for %d, got %v %v", i, v, ok)
+		}
+	}
+
+	if removed := c.Invalidate([]string{"even"}); removed != 32 {
+		t.Fatalf("expected 32 entries invalidated, got %d", removed)
+	}
+	if c.Contains(0) {
+		t.Fatalf("expected invalidated key to be gone")
+	}
+}
+
+func TestNextPow2(t *testing.T) {
+	cases := map[int]int{0: 1, 1: 1, 2: 2, 3: 4, 4: 4, 5: 8, 8: 8, 9: 16}
+	for in, want := range cases {
+		if got := nextPow2(in); got != want {
+			t.Fatalf("nextPow2(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package tci
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_ConcurrentSweeperStartStop(t *testing.T) {
+	c, err := New(4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.StartExpirySweeper(time.Millisecond)
+			c.Stop()
+		}()
+	}
+	wg.Wait()
+	c.Stop()
+}
+
+// TestCache_ConcurrentContainsPeekAfterExpiry exercises the race the
+// reviewer reported: Contains/Peek physically remove an expired entry,
+// which mutates the underlying LRU, so they must not run under only a
+// read lock while other goroutines are doing the same.
+func TestCache_ConcurrentContainsPeekAfterExpiry(t *testing.T) {
+	c, err := NewWithDefaultTTL(4, time.Millisecond, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	c.Add("k", "v")
+	time.Sleep(5 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Contains("k")
+			c.Peek("k")
+		}()
+	}
+	wg.Wait()
+}
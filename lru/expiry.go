@@ -0,0 +1,74 @@
+package lru
+
+import (
+	"container/heap"
+	"container/list"
+	"time"
+)
+
+// expiryHeap is a min-heap of *list.Element ordered by entry.expiresAt. It
+// only ever holds elements whose entry has a non-zero TTL, since recency
+// order (the evictList) and expiry order diverge whenever entries carry
+// different TTLs or are re-accessed via Get.
+type expiryHeap []*list.Element
+
+func (h expiryHeap) Len() int { return len(h) }
+
+func (h expiryHeap) Less(i, j int) bool {
+	return h[i].Value.(*entry).expiresAt.Before(h[j].Value.(*entry).expiresAt)
+}
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].Value.(*entry).heapIndex = i
+	h[j].Value.(*entry).heapIndex = j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	el := x.(*list.Element)
+	el.Value.(*entry).heapIndex = len(*h)
+	*h = append(*h, el)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	el := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	el.Value.(*entry).heapIndex = -1
+	return el
+}
+
+// push adds el to the heap if it carries a TTL.
+func (h *expiryHeap) push(el *list.Element, expiresAt time.Time) {
+	if expiresAt.IsZero() {
+		el.Value.(*entry).heapIndex = -1
+		return
+	}
+	heap.Push(h, el)
+}
+
+// remove drops el from the heap if it is present.
+func (h *expiryHeap) remove(el *list.Element) {
+	i := el.Value.(*entry).heapIndex
+	if i < 0 || i >= len(*h) {
+		return
+	}
+	heap.Remove(h, i)
+}
+
+// update repositions el after its expiresAt has changed, adding or removing
+// it from the heap as needed.
+func (h *expiryHeap) update(el *list.Element, expiresAt time.Time) {
+	h.remove(el)
+	h.push(el, expiresAt)
+}
+
+// peek returns the element with the soonest expiry, if any entries carry a TTL.
+func (h expiryHeap) peek() (*list.Element, bool) {
+	if len(h) == 0 {
+		return nil, false
+	}
+	return h[0], true
+}
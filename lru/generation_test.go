@@ -0,0 +1,99 @@
+package lru
+
+import "testing"
+
+func TestLRU_InvalidateIsLazy(t *testing.T) {
+	l, err := NewLRU(4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1, "tag-1")
+	l.Add("b", 2)
+
+	if removed := l.Invalidate([]string{"tag-1"}); removed != 1 {
+		t.Fatalf("expected 1 entry marked dead, got %d", removed)
+	}
+
+	// Still physically present until touched.
+	if l.evictList.Len() != 2 {
+		t.Fatalf("expected invalidate to defer physical removal, got len %d", l.evictList.Len())
+	}
+	if l.Len() != 1 {
+		t.Fatalf("expected logical Len to exclude the tombstone, got %d", l.Len())
+	}
+
+	if _, ok := l.Get("a"); ok {
+		t.Fatalf("expected invalidated entry to be a miss")
+	}
+	if l.evictList.Len() != 1 {
+		t.Fatalf("expected Get to lazily remove the tombstone, got len %d", l.evictList.Len())
+	}
+}
+
+func TestLRU_PurgeIsImmediate(t *testing.T) {
+	l, err := NewLRU(4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1)
+	l.Add("b", 2)
+	l.Purge()
+
+	if l.Len() != 0 {
+		t.Fatalf("expected empty cache after purge, got len %d", l.Len())
+	}
+	if _, ok := l.Get("a"); ok {
+		t.Fatalf("expected purge to drop all entries")
+	}
+}
+
+func TestLRU_InvalidateDoesNotDoubleCountTombstones(t *testing.T) {
+	l, err := NewLRU(4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1, "tag-1")
+	l.Add("b", 2, "tag-1")
+	l.Add("c", 3)
+
+	if removed := l.Invalidate([]string{"tag-1"}); removed != 2 {
+		t.Fatalf("expected 2 entries marked dead, got %d", removed)
+	}
+	// Invalidating the same tag again before anything touches the
+	// stranded entries must not recount them.
+	if removed := l.Invalidate([]string{"tag-1"}); removed != 0 {
+		t.Fatalf("expected repeat invalidate to mark nothing new, got %d", removed)
+	}
+
+	if l.Len() != 1 {
+		t.Fatalf("expected only the untagged entry to be live, got %d", l.Len())
+	}
+	if removed := l.Compact(); removed != 2 {
+		t.Fatalf("expected 2 tombstones compacted, got %d", removed)
+	}
+	if l.Len() != 1 {
+		t.Fatalf("expected Len to still report the surviving entry after compact, got %d", l.Len())
+	}
+}
+
+func TestLRU_Compact(t *testing.T) {
+	l, err := NewLRU(4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1, "tag-1")
+	l.Add("b", 2, "tag-1")
+	l.Add("c", 3)
+	l.Invalidate([]string{"tag-1"})
+
+	if removed := l.Compact(); removed != 2 {
+		t.Fatalf("expected 2 tombstones compacted, got %d", removed)
+	}
+	if l.evictList.Len() != 1 {
+		t.Fatalf("expected only the untagged entry to remain, got len %d", l.evictList.Len())
+	}
+}
@@ -3,72 +3,145 @@ package lru
 import (
 	"container/list"
 	"errors"
+	"time"
 )
 
-// EvictCallback is used to get a callback when a cache entry is evicted
+// EvictCallback is used to get a callback when a cache entry is evicted.
+// Every eviction path except Purge fires it synchronously while the
+// caller's lock is held, so callbacks are serialized against each other and
+// against the rest of the cache. Purge is the exception: it fires the
+// callback for every purged entry from a background goroutine (see Purge),
+// so a callback that isn't itself safe to call concurrently, or that
+// assumes it won't still be running after Purge returns, needs to guard
+// against that case itself.
 type EvictCallback func(key interface{}, value interface{})
 
 // LRU implements a non-thread safe fixed size LRU cache
 type LRU struct {
-	size      int
-	evictList *list.List
-	items     map[interface{}]*list.Element
-	tags      map[string]map[*list.Element]bool
-	onEvict   EvictCallback
+	size       int
+	evictList  *list.List
+	items      map[interface{}]*list.Element
+	tags       map[string]map[*list.Element]bool
+	onEvict    EvictCallback
+	defaultTTL time.Duration
+	expiries   expiryHeap
+	generation uint64
+	tomb       int
 }
 
 // entry is used to hold a value in the evictList
 type entry struct {
-	key   interface{}
-	value interface{}
-	tags  []string
+	key        interface{}
+	value      interface{}
+	tags       []string
+	expiresAt  time.Time
+	heapIndex  int
+	generation uint64
+	tombstoned bool
+}
+
+// expired reports whether the entry's TTL has elapsed.
+func (e *entry) expired() bool {
+	return !e.expiresAt.IsZero() && time.Now().After(e.expiresAt)
 }
 
 // NewLRU constructs an LRU of the given size
 func NewLRU(size int, onEvict EvictCallback) (*LRU, error) {
+	return NewLRUWithDefaultTTL(size, 0, onEvict)
+}
+
+// NewLRUWithDefaultTTL constructs a fixed size LRU whose entries expire
+// after defaultTTL unless overridden per-entry via AddWithTTL. A defaultTTL
+// of 0 disables expiry, matching NewLRU.
+func NewLRUWithDefaultTTL(size int, defaultTTL time.Duration, onEvict EvictCallback) (*LRU, error) {
 	if size <= 0 {
 		return nil, errors.New("Must provide a positive size")
 	}
 	c := &LRU{
-		size:      size,
-		evictList: list.New(),
-		items:     make(map[interface{}]*list.Element),
-		tags:      make(map[string]map[*list.Element]bool),
-		onEvict:   onEvict,
+		size:       size,
+		evictList:  list.New(),
+		items:      make(map[interface{}]*list.Element),
+		tags:       make(map[string]map[*list.Element]bool),
+		onEvict:    onEvict,
+		defaultTTL: defaultTTL,
 	}
 	return c, nil
 }
 
-// Purge is used to completely clear the cache.
+// Purge is used to completely clear the cache. Purging is O(1): the
+// generation counter is bumped (stranding any entry still reachable from
+// elsewhere as dead) and the list/map/tag bookkeeping is simply replaced,
+// rather than walked and torn down entry by entry. If an eviction callback
+// is set, it is still fired once per purged entry, but from a background
+// goroutine so Purge itself does not pay for it. This breaks the
+// serialization guarantee every other eviction path gives the callback:
+// purged entries' callbacks may run concurrently with each other, with
+// callbacks from other evictions, and with no cache lock held, for some
+// unbounded time after Purge returns. See EvictCallback.
 func (c *LRU) Purge() {
-	for k, v := range c.items {
-		if c.onEvict != nil {
-			c.onEvict(k, v.Value.(*entry).value)
-		}
-		delete(c.items, k)
-	}
+	oldItems := c.items
+	c.generation++
 	c.evictList.Init()
+	c.items = make(map[interface{}]*list.Element)
 	c.tags = make(map[string]map[*list.Element]bool)
+	c.expiries = nil
+	c.tomb = 0
+
+	if c.onEvict != nil {
+		go func() {
+			for _, el := range oldItems {
+				ent := el.Value.(*entry)
+				c.onEvict(ent.key, ent.value)
+			}
+		}()
+	}
 }
 
 // Add adds a value to the cache and registers the tags by which can be invalidated.
 // Returns true if an eviction occurred.
 func (c *LRU) Add(key, value interface{}, tags ...string) (evicted bool) {
+	return c.AddWithTTL(key, value, c.defaultTTL, tags...)
+}
+
+// AddWithTTL adds a value to the cache with a per-entry TTL, overriding any
+// defaultTTL configured via NewLRUWithDefaultTTL. A ttl of 0 means the entry
+// never expires. Returns true if an eviction occurred.
+func (c *LRU) AddWithTTL(key, value interface{}, ttl time.Duration, tags ...string) (evicted bool) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
 	// Check for existing item
 	if el, ok := c.items[key]; ok {
+		ent := el.Value.(*entry)
+		if c.tomb > 0 && ent.tombstoned {
+			c.tomb--
+		}
 		c.evictList.MoveToFront(el)
 		c.untag(el, tags)
-		el.Value.(*entry).value = value
-		el.Value.(*entry).tags = tags
+		ent.value = value
+		ent.tags = tags
+		ent.expiresAt = expiresAt
+		ent.generation = c.generation
+		ent.tombstoned = false
 		c.tag(el, tags)
+		c.expiries.update(el, expiresAt)
 		return false
 	}
 
 	// Add new item
-	ent := &entry{key, value, tags}
+	ent := &entry{
+		key:        key,
+		value:      value,
+		tags:       tags,
+		expiresAt:  expiresAt,
+		generation: c.generation,
+	}
 	el := c.evictList.PushFront(ent)
 	c.tag(el, tags)
 	c.items[key] = el
+	c.expiries.push(el, expiresAt)
 
 	evict := c.evictList.Len() > c.size
 	// Verify size not exceeded
@@ -80,9 +153,14 @@ func (c *LRU) Add(key, value interface{}, tags ...string) (evicted bool) {
 
 // Get looks up a key's value from the cache.
 func (c *LRU) Get(key interface{}) (value interface{}, ok bool) {
-	if ent, ok := c.items[key]; ok {
-		c.evictList.MoveToFront(ent)
-		return ent.Value.(*entry).value, true
+	if el, ok := c.items[key]; ok {
+		ent := el.Value.(*entry)
+		if c.dead(ent) {
+			c.removeElement(el)
+			return nil, false
+		}
+		c.evictList.MoveToFront(el)
+		return ent.value, true
 	}
 	return
 }
@@ -90,18 +168,30 @@ func (c *LRU) Get(key interface{}) (value interface{}, ok bool) {
 // Contains checks if a key is in the cache, without updating the recent-ness
 // or deleting it for being stale.
 func (c *LRU) Contains(key interface{}) (ok bool) {
-	_, ok = c.items[key]
-	return ok
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	if c.dead(el.Value.(*entry)) {
+		c.removeElement(el)
+		return false
+	}
+	return true
 }
 
 // Peek returns the key value (or undefined if not found) without updating
 // the "recently used"-ness of the key.
 func (c *LRU) Peek(key interface{}) (value interface{}, ok bool) {
-	var el *list.Element
-	if el, ok = c.items[key]; ok {
-		return el.Value.(*entry).value, true
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	ent := el.Value.(*entry)
+	if c.dead(ent) {
+		c.removeElement(el)
+		return nil, false
 	}
-	return nil, ok
+	return ent.value, true
 }
 
 // Remove removes the provided key from the cache, returning if the
@@ -146,9 +236,19 @@ func (c *LRU) Keys() []interface{} {
 	return keys
 }
 
-// Len returns the number of items in the cache.
+// Len returns the number of live items in the cache. Invalidate lazily
+// marks entries dead without removing them, so this subtracts the running
+// tombstone count from the physical list length; the count is exact, not an
+// approximation, since each entry is tombstoned (and counted) at most once
+// regardless of how many times Invalidate is called against it. Entries
+// whose TTL has elapsed but that have not yet been removed (by an access,
+// SweepExpired or Compact) are still counted.
 func (c *LRU) Len() int {
-	return c.evictList.Len()
+	n := c.evictList.Len() - c.tomb
+	if n < 0 {
+		return 0
+	}
+	return n
 }
 
 // Resize changes the cache size.
@@ -176,34 +276,98 @@ func (c *LRU) removeOldest() {
 func (c *LRU) removeElement(el *list.Element) {
 	c.evictList.Remove(el)
 	ent := el.Value.(*entry)
+	if c.tomb > 0 && ent.tombstoned {
+		c.tomb--
+	}
 	c.untag(el, ent.tags)
+	c.expiries.remove(el)
 	delete(c.items, ent.key)
 	if c.onEvict != nil {
 		c.onEvict(ent.key, ent.value)
 	}
 }
 
-// Invalidate invalidates a tag, purging all associated keys from the cache.
+// dead reports whether an entry should be treated as absent: its TTL has
+// elapsed, it predates the cache's current generation (a Purge happened
+// since it was added), or one of its tags was invalidated since it was
+// tagged.
+func (c *LRU) dead(ent *entry) bool {
+	return ent.expired() || c.stale(ent)
+}
+
+// stale reports whether an entry predates the cache generation or has been
+// tombstoned by Invalidate.
+func (c *LRU) stale(ent *entry) bool {
+	return ent.generation != c.generation || ent.tombstoned
+}
+
+// Compact walks the cache and physically removes any entry that Invalidate
+// has lazily marked dead, or whose TTL has elapsed, but that no access has
+// touched since, firing the eviction callback for each one removed.
+func (c *LRU) Compact() (removed int) {
+	var next *list.Element
+	for el := c.evictList.Back(); el != nil; el = next {
+		next = el.Prev()
+		if c.dead(el.Value.(*entry)) {
+			c.removeElement(el)
+			removed++
+		}
+	}
+	return removed
+}
+
+// SweepExpired removes every entry whose TTL has elapsed, using the expiry
+// heap so cost is O(k log n) in the number of expired entries rather than a
+// full scan of the cache.
+func (c *LRU) SweepExpired() (removed int) {
+	now := time.Now()
+	for {
+		el, ok := c.expiries.peek()
+		if !ok || now.Before(el.Value.(*entry).expiresAt) {
+			return removed
+		}
+		c.removeElement(el)
+		removed++
+	}
+}
+
+// Invalidate invalidates a set of tags: each matching entry is marked
+// tombstoned in place rather than being unlinked and having its eviction
+// callback fired under the write lock. Tombstoned entries are lazily
+// removed (and only then does the eviction callback fire) the next time
+// they're looked up via Get/Peek/Contains, or in bulk via Compact. Each
+// entry carries its own tombstoned bit, so it is counted at most once by
+// Invalidate no matter how many times it's called, or how many of the
+// entry's tags are invalidated across those calls — unlike a generation
+// counter per tag, this doesn't require pruning any bookkeeping keyed on
+// tag cardinality.
 func (c *LRU) Invalidate(tags []string) (removed int) {
 	for _, tag := range tags {
-		if els, ok := c.tags[tag]; ok {
-			for el, _ := range els {
-				c.removeElement(el)
+		for el := range c.tags[tag] {
+			ent := el.Value.(*entry)
+			if !ent.tombstoned {
+				ent.tombstoned = true
 				removed++
 			}
 		}
 	}
+	c.tomb += removed
 	return removed
 }
 
-// FindByTags returns all matching keys for a set of tags.
+// FindByTags returns all matching keys for a set of tags, lazily removing
+// (and excluding from the result) any entry it encounters that has since
+// expired or been invalidated.
 func (c *LRU) FindByTags(tags []string) (keys []interface{}) {
 	keys = []interface{}{}
 	for _, tag := range tags {
-		if els, ok := c.tags[tag]; ok {
-			for el, _ := range els {
-				keys = append(keys, el.Value.(*entry).key)
+		for el := range c.tags[tag] {
+			ent := el.Value.(*entry)
+			if c.dead(ent) {
+				c.removeElement(el)
+				continue
 			}
+			keys = append(keys, ent.key)
 		}
 	}
 	return
@@ -0,0 +1,243 @@
+package lru
+
+import (
+	"container/list"
+	"errors"
+)
+
+// EvictCallbackG is used to get a callback when a cache entry is evicted
+type EvictCallbackG[K comparable, V any] func(key K, value V)
+
+// LRUG implements a non-thread safe fixed size LRU cache using generics
+// instead of interface{}. It has LRU's recency eviction and tag support, but
+// not its TTL/expiry: Invalidate and Purge remove entries (and fire the
+// eviction callback) eagerly and synchronously rather than lazily.
+type LRUG[K comparable, V any] struct {
+	size      int
+	evictList *list.List
+	items     map[K]*list.Element
+	tags      map[string]map[*list.Element]bool
+	onEvict   EvictCallbackG[K, V]
+}
+
+// entryG is used to hold a value in the evictList
+type entryG[K comparable, V any] struct {
+	key   K
+	value V
+	tags  []string
+}
+
+// NewLRUG constructs an LRU of the given size
+func NewLRUG[K comparable, V any](size int, onEvict EvictCallbackG[K, V]) (*LRUG[K, V], error) {
+	if size <= 0 {
+		return nil, errors.New("Must provide a positive size")
+	}
+	c := &LRUG[K, V]{
+		size:      size,
+		evictList: list.New(),
+		items:     make(map[K]*list.Element),
+		tags:      make(map[string]map[*list.Element]bool),
+		onEvict:   onEvict,
+	}
+	return c, nil
+}
+
+// Purge is used to completely clear the cache.
+func (c *LRUG[K, V]) Purge() {
+	for k, v := range c.items {
+		if c.onEvict != nil {
+			c.onEvict(k, v.Value.(*entryG[K, V]).value)
+		}
+		delete(c.items, k)
+	}
+	c.evictList.Init()
+	c.tags = make(map[string]map[*list.Element]bool)
+}
+
+// Add adds a value to the cache and registers the tags by which can be invalidated.
+// Returns true if an eviction occurred.
+func (c *LRUG[K, V]) Add(key K, value V, tags ...string) (evicted bool) {
+	// Check for existing item
+	if el, ok := c.items[key]; ok {
+		c.evictList.MoveToFront(el)
+		c.untag(el, tags)
+		el.Value.(*entryG[K, V]).value = value
+		el.Value.(*entryG[K, V]).tags = tags
+		c.tag(el, tags)
+		return false
+	}
+
+	// Add new item
+	ent := &entryG[K, V]{key, value, tags}
+	el := c.evictList.PushFront(ent)
+	c.tag(el, tags)
+	c.items[key] = el
+
+	evict := c.evictList.Len() > c.size
+	// Verify size not exceeded
+	if evict {
+		c.removeOldest()
+	}
+	return evict
+}
+
+// Get looks up a key's value from the cache.
+func (c *LRUG[K, V]) Get(key K) (value V, ok bool) {
+	if ent, ok := c.items[key]; ok {
+		c.evictList.MoveToFront(ent)
+		return ent.Value.(*entryG[K, V]).value, true
+	}
+	return
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *LRUG[K, V]) Contains(key K) (ok bool) {
+	_, ok = c.items[key]
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *LRUG[K, V]) Peek(key K) (value V, ok bool) {
+	var el *list.Element
+	if el, ok = c.items[key]; ok {
+		return el.Value.(*entryG[K, V]).value, true
+	}
+	return
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *LRUG[K, V]) Remove(key K) (present bool) {
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *LRUG[K, V]) RemoveOldest() (key K, value V, ok bool) {
+	el := c.evictList.Back()
+	if el != nil {
+		c.removeElement(el)
+		ent := el.Value.(*entryG[K, V])
+		return ent.key, ent.value, true
+	}
+	return
+}
+
+// GetOldest returns the oldest entry
+func (c *LRUG[K, V]) GetOldest() (key K, value V, ok bool) {
+	el := c.evictList.Back()
+	if el != nil {
+		ent := el.Value.(*entryG[K, V])
+		return ent.key, ent.value, true
+	}
+	return
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *LRUG[K, V]) Keys() []K {
+	keys := make([]K, len(c.items))
+	i := 0
+	for el := c.evictList.Back(); el != nil; el = el.Prev() {
+		keys[i] = el.Value.(*entryG[K, V]).key
+		i++
+	}
+	return keys
+}
+
+// Values returns a slice of the values in the cache, from oldest to newest.
+func (c *LRUG[K, V]) Values() []V {
+	values := make([]V, len(c.items))
+	i := 0
+	for el := c.evictList.Back(); el != nil; el = el.Prev() {
+		values[i] = el.Value.(*entryG[K, V]).value
+		i++
+	}
+	return values
+}
+
+// Len returns the number of items in the cache.
+func (c *LRUG[K, V]) Len() int {
+	return c.evictList.Len()
+}
+
+// Resize changes the cache size.
+func (c *LRUG[K, V]) Resize(size int) (evicted int) {
+	diff := c.Len() - size
+	if diff < 0 {
+		diff = 0
+	}
+	for i := 0; i < diff; i++ {
+		c.removeOldest()
+	}
+	c.size = size
+	return diff
+}
+
+// removeOldest removes the oldest item from the cache.
+func (c *LRUG[K, V]) removeOldest() {
+	el := c.evictList.Back()
+	if el != nil {
+		c.removeElement(el)
+	}
+}
+
+// removeElement is used to remove a given list element from the cache
+func (c *LRUG[K, V]) removeElement(el *list.Element) {
+	c.evictList.Remove(el)
+	ent := el.Value.(*entryG[K, V])
+	c.untag(el, ent.tags)
+	delete(c.items, ent.key)
+	if c.onEvict != nil {
+		c.onEvict(ent.key, ent.value)
+	}
+}
+
+// Invalidate invalidates a tag, purging all associated keys from the cache.
+func (c *LRUG[K, V]) Invalidate(tags []string) (removed int) {
+	for _, tag := range tags {
+		if els, ok := c.tags[tag]; ok {
+			for el, _ := range els {
+				c.removeElement(el)
+				removed++
+			}
+		}
+	}
+	return removed
+}
+
+// FindByTags returns all matching keys for a set of tags.
+func (c *LRUG[K, V]) FindByTags(tags []string) (keys []K) {
+	for _, tag := range tags {
+		if els, ok := c.tags[tag]; ok {
+			for el, _ := range els {
+				keys = append(keys, el.Value.(*entryG[K, V]).key)
+			}
+		}
+	}
+	return
+}
+
+// tag adds a key to the invalidation list
+func (c *LRUG[K, V]) tag(el *list.Element, tags []string) {
+	for _, tag := range tags {
+		if _, ok := c.tags[tag]; !ok {
+			c.tags[tag] = make(map[*list.Element]bool)
+		}
+		c.tags[tag][el] = true
+	}
+}
+
+// untag removes a key from the invalidation list
+func (c *LRUG[K, V]) untag(el *list.Element, tags []string) {
+	for _, tag := range tags {
+		delete(c.tags[tag], el)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
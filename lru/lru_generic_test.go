@@ -0,0 +1,163 @@
+package lru
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLRUG_AddGetPeekContains(t *testing.T) {
+	l, err := NewLRUG[string, int](2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1)
+	l.Add("b", 2)
+
+	if v, ok := l.Peek("a"); !ok || v != 1 {
+		t.Fatalf("expected peek hit for a=1, got %v %v", v, ok)
+	}
+	if !l.Contains("b") {
+		t.Fatalf("expected b to be contained")
+	}
+	if v, ok := l.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected get hit for a=1, got %v %v", v, ok)
+	}
+
+	// "a" was just touched by Get, so adding a third key evicts "b".
+	l.Add("c", 3)
+	if l.Contains("b") {
+		t.Fatalf("expected b to be evicted as least recently used")
+	}
+	if !l.Contains("a") || !l.Contains("c") {
+		t.Fatalf("expected a and c to remain")
+	}
+}
+
+func TestLRUG_Remove(t *testing.T) {
+	l, err := NewLRUG[string, int](4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1)
+	if !l.Remove("a") {
+		t.Fatalf("expected a to be present for removal")
+	}
+	if l.Remove("a") {
+		t.Fatalf("expected second removal of a to report absent")
+	}
+	if l.Contains("a") {
+		t.Fatalf("expected a to be gone")
+	}
+}
+
+func TestLRUG_InvalidateAndFindByTags(t *testing.T) {
+	l, err := NewLRUG[string, int](4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1, "tag-1")
+	l.Add("b", 2, "tag-1")
+	l.Add("c", 3, "tag-2")
+
+	found := l.FindByTags([]string{"tag-1"})
+	if len(found) != 2 {
+		t.Fatalf("expected 2 keys tagged tag-1, got %d", len(found))
+	}
+
+	if removed := l.Invalidate([]string{"tag-1"}); removed != 2 {
+		t.Fatalf("expected 2 entries invalidated, got %d", removed)
+	}
+	if l.Contains("a") || l.Contains("b") {
+		t.Fatalf("expected tag-1 entries to be gone")
+	}
+	if !l.Contains("c") {
+		t.Fatalf("expected c to survive invalidating tag-1")
+	}
+	if found := l.FindByTags([]string{"tag-2"}); len(found) != 1 || found[0] != "c" {
+		t.Fatalf("expected FindByTags to still find c under tag-2, got %v", found)
+	}
+}
+
+func TestLRUG_ValuesOldestToNewest(t *testing.T) {
+	l, err := NewLRUG[string, int](4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1)
+	l.Add("b", 2)
+	l.Add("c", 3)
+
+	values := l.Values()
+	want := []int{1, 2, 3}
+	if len(values) != len(want) {
+		t.Fatalf("expected %d values, got %d", len(want), len(values))
+	}
+	for i, v := range want {
+		if values[i] != v {
+			t.Fatalf("expected Values()[%d] = %d, got %d", i, v, values[i])
+		}
+	}
+
+	// Touching "a" moves it to the front of the list, so Values(), which
+	// always walks back-to-front, now reports it as the newest.
+	l.Get("a")
+	values = l.Values()
+	want = []int{2, 3, 1}
+	for i, v := range want {
+		if values[i] != v {
+			t.Fatalf("expected Values()[%d] = %d after Get(a), got %d", i, v, values[i])
+		}
+	}
+}
+
+// Benchmarks comparing the generic LRUG against LRU for the no-tag case.
+func BenchmarkTCIG_Rand_NoTags(b *testing.B) {
+	l, err := NewLRUG[int64, int64](8192, nil)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+
+	trace := make([]int64, b.N*2)
+	for i := 0; i < b.N*2; i++ {
+		trace[i] = rand.Int63() % 32768
+	}
+
+	b.ResetTimer()
+
+	var hit, miss int
+	for i := 0; i < 2*b.N; i++ {
+		if i%2 == 0 {
+			l.Add(trace[i], trace[i])
+		} else {
+			_, ok := l.Get(trace[i])
+			if ok {
+				hit++
+			} else {
+				miss++
+			}
+		}
+	}
+	b.Logf("hit: %d miss: %d ratio: %f", hit, miss, float64(hit)/float64(miss))
+}
+
+func BenchmarkTCIG_Rand_Add_NoTags(b *testing.B) {
+	l, err := NewLRUG[int64, int64](8192, nil)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+
+	trace := make([]int64, b.N)
+	for i := 0; i < b.N; i++ {
+		trace[i] = rand.Int63() % 32768
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l.Add(trace[i], trace[i])
+	}
+}
@@ -0,0 +1,45 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU_AddWithTTL_ExpiresOnGet(t *testing.T) {
+	l, err := NewLRU(2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.AddWithTTL("k", "v", time.Millisecond, "tag-1")
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := l.Get("k"); ok {
+		t.Fatalf("expected expired entry to be treated as a miss")
+	}
+	if l.Len() != 0 {
+		t.Fatalf("expected expired entry to be removed, got len %d", l.Len())
+	}
+	if found := l.FindByTags([]string{"tag-1"}); len(found) != 0 {
+		t.Fatalf("expected tag bookkeeping to be cleaned up, got %v", found)
+	}
+}
+
+func TestLRU_SweepExpired(t *testing.T) {
+	l, err := NewLRUWithDefaultTTL(4, time.Millisecond, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Add("a", 1)
+	l.Add("b", 2)
+	l.AddWithTTL("c", 3, time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	if removed := l.SweepExpired(); removed != 2 {
+		t.Fatalf("expected 2 expired entries swept, got %d", removed)
+	}
+	if l.Len() != 1 {
+		t.Fatalf("expected 1 entry remaining, got %d", l.Len())
+	}
+}
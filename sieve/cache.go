@@ -0,0 +1,108 @@
+package sieve
+
+import (
+	"sync"
+)
+
+// Cache is a thread-safe fixed size SIEVE cache.
+type Cache struct {
+	sieve *Sieve
+	lock  sync.RWMutex
+}
+
+// New creates a SIEVE cache of the given size.
+func New(size int) (*Cache, error) {
+	return NewWithEvict(size, nil)
+}
+
+// NewWithEvict constructs a fixed size cache with the given eviction
+// callback.
+func NewWithEvict(size int, onEvicted func(key interface{}, value interface{})) (*Cache, error) {
+	s, err := NewSieve(size, EvictCallback(onEvicted))
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{sieve: s}, nil
+}
+
+// Purge is used to completely clear the cache.
+func (c *Cache) Purge() {
+	c.lock.Lock()
+	c.sieve.Purge()
+	c.lock.Unlock()
+}
+
+// Add adds a value to the cache. Returns true if an eviction occurred.
+func (c *Cache) Add(key, value interface{}, tags ...string) (evicted bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.sieve.Add(key, value, tags...)
+}
+
+// Get looks up a key's value from the cache. Marking an entry visited only
+// ever writes a single bit via atomic.Bool, so Get only needs a read lock.
+func (c *Cache) Get(key interface{}) (value interface{}, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.sieve.Get(key)
+}
+
+// Contains checks if a key is in the cache, without marking it visited.
+func (c *Cache) Contains(key interface{}) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.sieve.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without marking it
+// visited.
+func (c *Cache) Peek(key interface{}) (value interface{}, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.sieve.Peek(key)
+}
+
+// Remove removes the provided key from the cache.
+func (c *Cache) Remove(key interface{}) {
+	c.lock.Lock()
+	c.sieve.Remove(key)
+	c.lock.Unlock()
+}
+
+// Resize changes the cache size.
+func (c *Cache) Resize(size int) (evicted int) {
+	c.lock.Lock()
+	evicted = c.sieve.Resize(size)
+	c.lock.Unlock()
+	return evicted
+}
+
+// Invalidate invalidates a tag, purging all associated keys from the cache.
+func (c *Cache) Invalidate(tags []string) (removed int) {
+	c.lock.Lock()
+	removed = c.sieve.Invalidate(tags)
+	c.lock.Unlock()
+	return removed
+}
+
+// FindByTags returns all matching keys for a set of tags.
+func (c *Cache) FindByTags(tags []string) (found []interface{}) {
+	c.lock.Lock()
+	found = c.sieve.FindByTags(tags)
+	c.lock.Unlock()
+	return found
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *Cache) Keys() []interface{} {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.sieve.Keys()
+}
+
+// Len returns the number of items in the cache.
+func (c *Cache) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.sieve.Len()
+}
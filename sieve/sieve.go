@@ -0,0 +1,265 @@
+// Package sieve implements the SIEVE eviction algorithm with the same tag
+// invalidation semantics as the lru package. SIEVE keeps entries in a FIFO
+// queue and evicts using a single "visited" bit per entry plus a hand
+// pointer that persists across evictions, which gives LRU-comparable hit
+// ratios at substantially less cost per Get: a Get only ever sets a bit, it
+// never splices the queue.
+package sieve
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// EvictCallback is used to get a callback when a cache entry is evicted
+type EvictCallback func(key interface{}, value interface{})
+
+// node is a single entry in the FIFO queue
+type node struct {
+	key     interface{}
+	value   interface{}
+	tags    []string
+	visited atomic.Bool
+	prev    *node // toward the head (newest)
+	next    *node // toward the tail (oldest)
+}
+
+// Sieve implements a non-thread safe fixed size SIEVE cache
+type Sieve struct {
+	size    int
+	len     int
+	head    *node // most recently inserted entry
+	tail    *node // oldest entry
+	hand    *node // eviction cursor, persists across evictions
+	items   map[interface{}]*node
+	tags    map[string]map[*node]bool
+	onEvict EvictCallback
+}
+
+// NewSieve constructs a SIEVE cache of the given size
+func NewSieve(size int, onEvict EvictCallback) (*Sieve, error) {
+	if size <= 0 {
+		return nil, errors.New("Must provide a positive size")
+	}
+	c := &Sieve{
+		size:    size,
+		items:   make(map[interface{}]*node),
+		tags:    make(map[string]map[*node]bool),
+		onEvict: onEvict,
+	}
+	return c, nil
+}
+
+// Purge is used to completely clear the cache.
+func (c *Sieve) Purge() {
+	if c.onEvict != nil {
+		for _, n := range c.items {
+			c.onEvict(n.key, n.value)
+		}
+	}
+	c.items = make(map[interface{}]*node)
+	c.tags = make(map[string]map[*node]bool)
+	c.head, c.tail, c.hand = nil, nil, nil
+	c.len = 0
+}
+
+// Add adds a value to the cache and registers the tags by which it can be
+// invalidated. Returns true if an eviction occurred.
+func (c *Sieve) Add(key, value interface{}, tags ...string) (evicted bool) {
+	if n, ok := c.items[key]; ok {
+		c.untag(n, n.tags)
+		n.value = value
+		n.tags = tags
+		c.tag(n, tags)
+		n.visited.Store(true)
+		return false
+	}
+
+	n := &node{key: key, value: value, tags: tags}
+	c.pushHead(n)
+	c.tag(n, tags)
+	c.items[key] = n
+	c.len++
+
+	evicted = c.len > c.size
+	if evicted {
+		c.evict()
+	}
+	return evicted
+}
+
+// Get looks up a key's value from the cache, marking it visited. Unlike an
+// LRU, this never moves the entry, so it is safe for callers to take only a
+// read lock around Get.
+func (c *Sieve) Get(key interface{}) (value interface{}, ok bool) {
+	n, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	n.visited.Store(true)
+	return n.value, true
+}
+
+// Contains checks if a key is in the cache, without marking it visited.
+func (c *Sieve) Contains(key interface{}) bool {
+	_, ok := c.items[key]
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without marking it
+// visited.
+func (c *Sieve) Peek(key interface{}) (value interface{}, ok bool) {
+	n, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	return n.value, true
+}
+
+// Remove removes the provided key from the cache, returning if the key was
+// contained.
+func (c *Sieve) Remove(key interface{}) (present bool) {
+	n, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.removeNode(n)
+	return true
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *Sieve) Keys() []interface{} {
+	keys := make([]interface{}, 0, c.len)
+	for n := c.tail; n != nil; n = n.prev {
+		keys = append(keys, n.key)
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *Sieve) Len() int {
+	return c.len
+}
+
+// Resize changes the cache size.
+func (c *Sieve) Resize(size int) (evicted int) {
+	for c.len > size {
+		c.evict()
+		evicted++
+	}
+	c.size = size
+	return evicted
+}
+
+// evict runs the SIEVE hand scan: starting from the current hand position
+// (or the tail, the oldest entry, the first time it runs) and walking
+// toward the head (wrapping back to the tail once it runs off the end), any
+// visited node is cleared and skipped; the first unvisited node found is
+// evicted, and the hand is left pointing at its predecessor so the next
+// eviction resumes from there. New entries are inserted at the head with
+// visited == false, so the hand reaches them only after it has passed every
+// entry between its current position and the head; unvisited entries behind
+// the hand are not considered before that.
+func (c *Sieve) evict() {
+	n := c.hand
+	if n == nil {
+		n = c.tail
+	}
+	for {
+		if n.visited.CompareAndSwap(true, false) {
+			n = n.prev
+			if n == nil {
+				n = c.tail
+			}
+			continue
+		}
+		c.hand = n.prev
+		c.removeNode(n)
+		return
+	}
+}
+
+// pushHead inserts a new node at the head (newest position) with
+// visited == false.
+func (c *Sieve) pushHead(n *node) {
+	n.prev = nil
+	n.next = c.head
+	if c.head != nil {
+		c.head.prev = n
+	}
+	c.head = n
+	if c.tail == nil {
+		c.tail = n
+	}
+}
+
+// removeNode unlinks a node from the queue and map bookkeeping, advancing
+// the hand off of it if necessary, and fires the eviction callback.
+func (c *Sieve) removeNode(n *node) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		c.head = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		c.tail = n.prev
+	}
+	if c.hand == n {
+		c.hand = n.prev
+	}
+
+	c.untag(n, n.tags)
+	delete(c.items, n.key)
+	c.len--
+	if c.onEvict != nil {
+		c.onEvict(n.key, n.value)
+	}
+}
+
+// Invalidate invalidates a tag, purging all associated keys from the cache.
+func (c *Sieve) Invalidate(tags []string) (removed int) {
+	for _, tag := range tags {
+		if ns, ok := c.tags[tag]; ok {
+			for n := range ns {
+				c.removeNode(n)
+				removed++
+			}
+		}
+	}
+	return removed
+}
+
+// FindByTags returns all matching keys for a set of tags.
+func (c *Sieve) FindByTags(tags []string) (keys []interface{}) {
+	keys = []interface{}{}
+	for _, tag := range tags {
+		if ns, ok := c.tags[tag]; ok {
+			for n := range ns {
+				keys = append(keys, n.key)
+			}
+		}
+	}
+	return
+}
+
+// tag adds a node to the invalidation list
+func (c *Sieve) tag(n *node, tags []string) {
+	for _, tag := range tags {
+		if _, ok := c.tags[tag]; !ok {
+			c.tags[tag] = make(map[*node]bool)
+		}
+		c.tags[tag][n] = true
+	}
+}
+
+// untag removes a node from the invalidation list
+func (c *Sieve) untag(n *node, tags []string) {
+	for _, tag := range tags {
+		delete(c.tags[tag], n)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package sieve
+
+import "testing"
+
+func TestSieve_VisitedSurvivesOneEvictionPass(t *testing.T) {
+	s, err := NewSieve(2, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	s.Add("a", 1)
+	s.Add("b", 2)
+	s.Get("a") // mark "a" visited so it survives the first eviction pass
+
+	s.Add("c", 3) // full: hand scan clears "a"'s bit, then evicts "b"
+
+	if !s.Contains("a") {
+		t.Fatalf("expected visited entry to survive eviction")
+	}
+	if s.Contains("b") {
+		t.Fatalf("expected unvisited entry to be evicted")
+	}
+	if !s.Contains("c") {
+		t.Fatalf("expected newly added entry to be present")
+	}
+}
+
+func TestSieve_InvalidateByTag(t *testing.T) {
+	s, err := NewSieve(4, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	s.Add("a", 1, "tag-1")
+	s.Add("b", 2, "tag-1")
+	s.Add("c", 3)
+
+	if removed := s.Invalidate([]string{"tag-1"}); removed != 2 {
+		t.Fatalf("expected 2 removed, got %d", removed)
+	}
+	if s.Len() != 1 {
+		t.Fatalf("expected 1 entry remaining, got %d", s.Len())
+	}
+}
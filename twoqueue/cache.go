@@ -0,0 +1,114 @@
+package twoqueue
+
+import (
+	"sync"
+)
+
+// Cache is a thread-safe fixed size 2Q cache.
+type Cache struct {
+	tq   *TwoQueue
+	lock sync.RWMutex
+}
+
+// New creates a 2Q cache of the given size.
+func New(size int) (*Cache, error) {
+	tq, err := NewTwoQueue(size)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{tq: tq}, nil
+}
+
+// NewParams creates a 2Q cache of the given size, with recentRatio and
+// ghostRatio controlling the sizes of the recent and ghost lists.
+func NewParams(size int, recentRatio, ghostRatio float64) (*Cache, error) {
+	tq, err := NewTwoQueueParams(size, recentRatio, ghostRatio)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{tq: tq}, nil
+}
+
+// Add adds a value to the cache.
+func (c *Cache) Add(key, value interface{}, tags ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.tq.Add(key, value, tags...)
+}
+
+// Get looks up a key's value from the cache.
+func (c *Cache) Get(key interface{}) (value interface{}, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tq.Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating the
+// recent-ness. Takes the write lock, not a read lock: TwoQueue.Contains delegates to the underlying lru.LRU, which
+// physically removes a tombstoned or expired entry on this path.
+func (c *Cache) Contains(key interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tq.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key. Takes the write lock, not a read
+// lock, for the same reason as Contains.
+func (c *Cache) Peek(key interface{}) (value interface{}, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tq.Peek(key)
+}
+
+// Remove removes the provided key from the cache.
+func (c *Cache) Remove(key interface{}) {
+	c.lock.Lock()
+	c.tq.Remove(key)
+	c.lock.Unlock()
+}
+
+// Resize changes the cache size.
+func (c *Cache) Resize(size int) (evicted int) {
+	c.lock.Lock()
+	evicted = c.tq.Resize(size)
+	c.lock.Unlock()
+	return evicted
+}
+
+// Invalidate invalidates a tag, purging all associated keys from the cache.
+func (c *Cache) Invalidate(tags []string) (removed int) {
+	c.lock.Lock()
+	removed = c.tq.Invalidate(tags)
+	c.lock.Unlock()
+	return removed
+}
+
+// FindByTags returns all matching keys for a set of tags.
+func (c *Cache) FindByTags(tags []string) (found []interface{}) {
+	c.lock.Lock()
+	found = c.tq.FindByTags(tags)
+	c.lock.Unlock()
+	return found
+}
+
+// Purge is used to completely clear the cache.
+func (c *Cache) Purge() {
+	c.lock.Lock()
+	c.tq.Purge()
+	c.lock.Unlock()
+}
+
+// Keys returns a slice of the keys in the cache.
+func (c *Cache) Keys() []interface{} {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.tq.Keys()
+}
+
+// Len returns the number of items in the cache.
+func (c *Cache) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.tq.Len()
+}
@@ -0,0 +1,37 @@
+package twoqueue
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestCache_ConcurrentContainsPeekDuringInvalidate exercises the race the
+// reviewer reported: Contains/Peek delegate to the underlying lru.LRU,
+// which physically removes a tombstoned entry on this path, so they must
+// not run under only a read lock while Invalidate is tombstoning entries
+// concurrently.
+func TestCache_ConcurrentContainsPeekDuringInvalidate(t *testing.T) {
+	c, err := New(4)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		c.Add(i, i, "tag-1")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			c.Contains(key % 4)
+			c.Peek(key % 4)
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c.Invalidate([]string{"tag-1"})
+	}()
+	wg.Wait()
+}
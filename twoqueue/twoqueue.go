@@ -0,0 +1,241 @@
+// Package twoqueue implements the 2Q cache eviction algorithm with the same
+// tag-based invalidation semantics as the lru package. 2Q tracks three
+// lists: recent (A1in), holding keys seen exactly once; frequent (Am),
+// holding keys seen more than once; and recentEvict (A1out), a ghost list
+// that remembers keys recently evicted from recent without holding their
+// values. A key that reappears while its ghost entry is still present is
+// promoted straight to frequent, which makes 2Q resistant to the kind of
+// one-shot scans that would otherwise thrash a plain LRU.
+package twoqueue
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kevburnsjr/tci-lru/lru"
+)
+
+const (
+	// Default2QRecentRatio is the default ratio of the cache size used for
+	// the recent (A1in) list.
+	Default2QRecentRatio = 0.25
+
+	// Default2QGhostEntries is the default ratio of the cache size used for
+	// the recentEvict (A1out) ghost list.
+	Default2QGhostEntries = 0.50
+)
+
+// qitem bundles a value with the tags it was added under, so that a key
+// promoted from recent to frequent (or re-added from a ghost entry) keeps
+// its tag bookkeeping intact.
+type qitem struct {
+	value interface{}
+	tags  []string
+}
+
+// TwoQueue implements a non-thread safe fixed size 2Q cache.
+type TwoQueue struct {
+	size        int
+	recentSize  int
+	recent      *lru.LRU
+	frequent    *lru.LRU
+	recentEvict *lru.LRU
+}
+
+// NewTwoQueue constructs a 2Q cache of the given size using the default
+// recent and ghost ratios.
+func NewTwoQueue(size int) (*TwoQueue, error) {
+	return NewTwoQueueParams(size, Default2QRecentRatio, Default2QGhostEntries)
+}
+
+// NewTwoQueueParams constructs a 2Q cache of the given size, with
+// recentRatio controlling the portion reserved for the recent list and
+// ghostRatio controlling the size of the recentEvict ghost list.
+func NewTwoQueueParams(size int, recentRatio, ghostRatio float64) (*TwoQueue, error) {
+	if size <= 0 {
+		return nil, errors.New("Must provide a positive size")
+	}
+	if recentRatio < 0.0 || recentRatio > 1.0 {
+		return nil, fmt.Errorf("Invalid recent ratio")
+	}
+	if ghostRatio < 0.0 || ghostRatio > 1.0 {
+		return nil, fmt.Errorf("Invalid ghost ratio")
+	}
+
+	recentSize := int(float64(size) * recentRatio)
+	if recentSize < 1 {
+		recentSize = 1
+	}
+	evictSize := int(float64(size) * ghostRatio)
+	if evictSize < 1 {
+		evictSize = 1
+	}
+
+	recent, err := lru.NewLRU(size, nil)
+	if err != nil {
+		return nil, err
+	}
+	frequent, err := lru.NewLRU(size, nil)
+	if err != nil {
+		return nil, err
+	}
+	recentEvict, err := lru.NewLRU(evictSize, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &TwoQueue{
+		size:        size,
+		recentSize:  recentSize,
+		recent:      recent,
+		frequent:    frequent,
+		recentEvict: recentEvict,
+	}
+	return c, nil
+}
+
+// Add adds a value to the cache and registers the tags by which it can be
+// invalidated.
+func (c *TwoQueue) Add(key, value interface{}, tags ...string) {
+	it := qitem{value, tags}
+
+	// Already frequent, update in place.
+	if c.frequent.Contains(key) {
+		c.frequent.Add(key, it, tags...)
+		return
+	}
+
+	// Key has proven worth keeping, promote it straight to frequent.
+	if c.recentEvict.Contains(key) {
+		c.ensureSpace(true)
+		c.recentEvict.Remove(key)
+		c.frequent.Add(key, it, tags...)
+		return
+	}
+
+	// Already in recent, just update its value.
+	if c.recent.Contains(key) {
+		c.recent.Add(key, it, tags...)
+		return
+	}
+
+	// New key, add to recent.
+	c.ensureSpace(false)
+	c.recent.Add(key, it, tags...)
+}
+
+// ensureSpace makes room for a new entry, shrinking recent down to
+// recentSize (spilling into the recentEvict ghost list) or, once recent is
+// already at its target size, evicting from frequent instead.
+func (c *TwoQueue) ensureSpace(recentEvict bool) {
+	recentLen := c.recent.Len()
+	freqLen := c.frequent.Len()
+	if recentLen+freqLen < c.size {
+		return
+	}
+
+	if recentLen > 0 && (recentLen > c.recentSize || (recentLen == c.recentSize && !recentEvict)) {
+		k, v, ok := c.recent.RemoveOldest()
+		if ok {
+			it := v.(qitem)
+			c.recentEvict.Add(k, qitem{tags: it.tags}, it.tags...)
+		}
+		return
+	}
+
+	c.frequent.RemoveOldest()
+}
+
+// Get looks up a key's value from the cache. A hit in recent promotes the
+// key to frequent.
+func (c *TwoQueue) Get(key interface{}) (value interface{}, ok bool) {
+	if v, ok := c.frequent.Get(key); ok {
+		return v.(qitem).value, true
+	}
+	if v, ok := c.recent.Peek(key); ok {
+		it := v.(qitem)
+		c.recent.Remove(key)
+		c.frequent.Add(key, it, it.tags...)
+		return it.value, true
+	}
+	return nil, false
+}
+
+// Contains checks if a key is in the cache, without updating the
+// recent-ness or promoting it. A dead entry (expired, or tombstoned by
+// Invalidate) is removed from the underlying list on this path.
+func (c *TwoQueue) Contains(key interface{}) bool {
+	return c.frequent.Contains(key) || c.recent.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key or promoting it.
+func (c *TwoQueue) Peek(key interface{}) (value interface{}, ok bool) {
+	if v, ok := c.frequent.Peek(key); ok {
+		return v.(qitem).value, true
+	}
+	if v, ok := c.recent.Peek(key); ok {
+		return v.(qitem).value, true
+	}
+	return nil, false
+}
+
+// Remove removes the provided key from the cache.
+func (c *TwoQueue) Remove(key interface{}) {
+	if c.frequent.Remove(key) {
+		return
+	}
+	if c.recent.Remove(key) {
+		return
+	}
+	c.recentEvict.Remove(key)
+}
+
+// Keys returns a slice of the keys in the cache, recent entries first,
+// each in oldest to newest order.
+func (c *TwoQueue) Keys() []interface{} {
+	return append(c.recent.Keys(), c.frequent.Keys()...)
+}
+
+// Len returns the number of live items in the cache.
+func (c *TwoQueue) Len() int {
+	return c.recent.Len() + c.frequent.Len()
+}
+
+// Purge is used to completely clear the cache.
+func (c *TwoQueue) Purge() {
+	c.recent.Purge()
+	c.frequent.Purge()
+	c.recentEvict.Purge()
+}
+
+// Resize changes the cache size, recalculating the recent and ghost
+// sub-sizes from the default ratios.
+func (c *TwoQueue) Resize(size int) (evicted int) {
+	recentSize := int(float64(size) * Default2QRecentRatio)
+	evictSize := int(float64(size) * Default2QGhostEntries)
+	c.size = size
+	c.recentSize = recentSize
+
+	evicted += c.frequent.Resize(size)
+	evicted += c.recent.Resize(size)
+	evicted += c.recentEvict.Resize(evictSize)
+	return evicted
+}
+
+// Invalidate invalidates a tag, purging all associated keys from recent and
+// frequent, and dropping any matching ghost entries so they are not
+// mistakenly promoted later.
+func (c *TwoQueue) Invalidate(tags []string) (removed int) {
+	removed += c.recent.Invalidate(tags)
+	removed += c.frequent.Invalidate(tags)
+	c.recentEvict.Invalidate(tags)
+	return removed
+}
+
+// FindByTags returns all matching keys for a set of tags.
+func (c *TwoQueue) FindByTags(tags []string) (keys []interface{}) {
+	keys = append(keys, c.recent.FindByTags(tags)...)
+	keys = append(keys, c.frequent.FindByTags(tags)...)
+	return keys
+}
@@ -0,0 +1,50 @@
+package twoqueue
+
+import "testing"
+
+func TestTwoQueue_GetPromotesRecentToFrequent(t *testing.T) {
+	q, err := NewTwoQueue(128)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	q.Add("k", "v", "tag-1")
+	if q.frequent.Contains("k") {
+		t.Fatalf("expected new key to start in recent")
+	}
+
+	if v, ok := q.Get("k"); !ok || v != "v" {
+		t.Fatalf("expected hit, got %v %v", v, ok)
+	}
+	if !q.frequent.Contains("k") {
+		t.Fatalf("expected key to be promoted to frequent after a hit")
+	}
+
+	if found := q.FindByTags([]string{"tag-1"}); len(found) != 1 || found[0] != "k" {
+		t.Fatalf("expected tag bookkeeping to follow promotion, got %v", found)
+	}
+}
+
+func TestTwoQueue_InvalidateRemovesGhostEntry(t *testing.T) {
+	q, err := NewTwoQueueParams(4, 0.25, 0.50)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	q.Add("a", 1, "tag-1")
+	q.Add("b", 2)
+	q.Add("c", 3)
+	q.Add("d", 4)
+	q.Add("e", 5) // recent is now over its target size, evicting "a" into the ghost list
+
+	if !q.recentEvict.Contains("a") {
+		t.Fatalf("expected evicted key to be tracked in the ghost list")
+	}
+
+	if removed := q.Invalidate([]string{"tag-1"}); removed != 0 {
+		t.Fatalf("expected no live removals, got %d", removed)
+	}
+	if q.recentEvict.Contains("a") {
+		t.Fatalf("expected ghost entry to be dropped on invalidation")
+	}
+}